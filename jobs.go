@@ -0,0 +1,301 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Job status values stored in the jobs table.
+const (
+	JobStatusPending = "pending"
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusFailed  = "failed"
+)
+
+// Job types understood by the registered handlers.
+const (
+	JobTypeFetchTMDBDetails     = "FetchTMDBDetails"
+	JobTypeFetchOMDBRatings     = "FetchOMDBRatings"
+	JobTypeFetchYouTubeTrailer  = "FetchYouTubeTrailer"
+	JobTypeFetchRecommendations = "FetchRecommendations"
+)
+
+// maxJobAttempts caps automatic retries before a job is left in JobStatusFailed.
+const maxJobAttempts = 3
+
+// jobRetention is how long a terminal (done or failed) job row is kept
+// before pruneTerminal removes it, so the jobs table doesn't grow without
+// bound on normal traffic.
+const jobRetention = 24 * time.Hour
+
+// jobPruneInterval is how often StartWorkers' background loop sweeps
+// terminal jobs older than jobRetention.
+const jobPruneInterval = time.Hour
+
+// Job is a persisted unit of enrichment work.
+type Job struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Payload   string `json:"payload"`
+	Status    string `json:"status"`
+	Result    string `json:"result,omitempty"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error,omitempty"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// JobHandler executes a job's payload and returns a JSON-encodable result.
+type JobHandler func(payload string) (string, error)
+
+// JobQueue is an in-process queue backed by the jobs table, consumed by a
+// pool of worker goroutines. Jobs survive restarts: pending or running rows
+// are requeued on startup.
+type JobQueue struct {
+	db       *sql.DB
+	queue    chan int64
+	handlers map[string]JobHandler
+}
+
+// NewJobQueue creates a queue bound to db. Handlers must be registered via
+// Register before StartWorkers is called.
+func NewJobQueue(db *sql.DB) *JobQueue {
+	return &JobQueue{
+		db:       db,
+		queue:    make(chan int64, 256),
+		handlers: make(map[string]JobHandler),
+	}
+}
+
+// Register associates a job type with the function that processes it.
+func (q *JobQueue) Register(jobType string, handler JobHandler) {
+	q.handlers[jobType] = handler
+}
+
+// Enqueue persists a pending job and hands it to the worker pool
+// asynchronously. The caller does not wait for completion.
+func (q *JobQueue) Enqueue(jobType string, payload interface{}) (int64, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := q.db.Exec(
+		"INSERT INTO jobs (type, payload, status) VALUES (?, ?, ?)",
+		jobType, string(body), JobStatusPending,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	q.queue <- id
+	return id, nil
+}
+
+// Run persists a job and executes it synchronously on the calling goroutine,
+// returning its result. Use this when the caller needs the outcome
+// immediately (e.g. a cold-cache request); Enqueue is for pure background
+// refreshes.
+func (q *JobQueue) Run(jobType string, payload interface{}) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := q.db.Exec(
+		"INSERT INTO jobs (type, payload, status) VALUES (?, ?, ?)",
+		jobType, string(body), JobStatusPending,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return "", err
+	}
+
+	return q.process(id)
+}
+
+// StartWorkers launches n worker goroutines consuming from the queue,
+// requeues any job left pending or running from a previous process, and
+// starts a background loop pruning old terminal jobs.
+func (q *JobQueue) StartWorkers(n int) {
+	for i := 0; i < n; i++ {
+		go q.worker()
+	}
+	q.resumeUnfinished()
+	go q.pruneLoop()
+}
+
+// pruneLoop periodically deletes done/failed jobs older than jobRetention.
+func (q *JobQueue) pruneLoop() {
+	ticker := time.NewTicker(jobPruneInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if _, err := q.db.Exec(
+			"DELETE FROM jobs WHERE status IN (?, ?) AND updated_at < datetime('now', ?)",
+			JobStatusDone, JobStatusFailed, fmt.Sprintf("-%d seconds", int(jobRetention.Seconds())),
+		); err != nil {
+			log.Printf("prune jobs: %v", err)
+		}
+	}
+}
+
+func (q *JobQueue) worker() {
+	for id := range q.queue {
+		if _, err := q.process(id); err != nil {
+			log.Printf("job %d failed: %v", id, err)
+		}
+	}
+}
+
+// resumeUnfinished pushes jobs left pending or running (e.g. after a crash)
+// back onto the queue so they are retried after restart.
+func (q *JobQueue) resumeUnfinished() {
+	rows, err := q.db.Query("SELECT id FROM jobs WHERE status IN (?, ?)", JobStatusPending, JobStatusRunning)
+	if err != nil {
+		log.Printf("resume jobs: %v", err)
+		return
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		q.requeue(id)
+	}
+}
+
+// requeue hands id back to the worker pool without blocking the caller. A
+// blocking send here would deadlock if called from a worker goroutine itself
+// (process's retry path) while the queue is full and every other worker is
+// doing the same thing, since workers are the channel's only consumer; fall
+// back to an async send so the caller can keep draining the queue.
+func (q *JobQueue) requeue(id int64) {
+	select {
+	case q.queue <- id:
+	default:
+		go func() { q.queue <- id }()
+	}
+}
+
+// process runs the handler for a single job and records its outcome,
+// retrying up to maxJobAttempts times on failure.
+func (q *JobQueue) process(id int64) (string, error) {
+	var jobType, payload string
+	var attempts int
+	err := q.db.QueryRow("SELECT type, payload, attempts FROM jobs WHERE id = ?", id).Scan(&jobType, &payload, &attempts)
+	if err != nil {
+		return "", err
+	}
+
+	handler, ok := q.handlers[jobType]
+	if !ok {
+		q.markFailed(id, attempts, "no handler registered for "+jobType)
+		return "", nil
+	}
+
+	q.db.Exec("UPDATE jobs SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", JobStatusRunning, id)
+
+	result, err := handler(payload)
+	attempts++
+	if err != nil {
+		if attempts >= maxJobAttempts {
+			q.markFailed(id, attempts, err.Error())
+		} else {
+			q.db.Exec("UPDATE jobs SET status = ?, attempts = ?, last_error = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+				JobStatusPending, attempts, err.Error(), id)
+			q.requeue(id)
+		}
+		return "", err
+	}
+
+	q.db.Exec("UPDATE jobs SET status = ?, attempts = ?, result = ?, last_error = '', updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		JobStatusDone, attempts, result, id)
+	return result, nil
+}
+
+func (q *JobQueue) markFailed(id int64, attempts int, lastError string) {
+	q.db.Exec("UPDATE jobs SET status = ?, attempts = ?, last_error = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?",
+		JobStatusFailed, attempts, lastError, id)
+}
+
+// Get returns a single job by ID for status inspection.
+func (q *JobQueue) Get(id int64) (Job, error) {
+	var j Job
+	err := q.db.QueryRow(
+		"SELECT id, type, payload, status, result, attempts, last_error, created_at, updated_at FROM jobs WHERE id = ?", id,
+	).Scan(&j.ID, &j.Type, &j.Payload, &j.Status, &j.Result, &j.Attempts, &j.LastError, &j.CreatedAt, &j.UpdatedAt)
+	return j, err
+}
+
+// List returns the most recently updated jobs, newest first.
+func (q *JobQueue) List(limit int) ([]Job, error) {
+	rows, err := q.db.Query(
+		"SELECT id, type, payload, status, result, attempts, last_error, created_at, updated_at FROM jobs ORDER BY id DESC LIMIT ?", limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		var j Job
+		if err := rows.Scan(&j.ID, &j.Type, &j.Payload, &j.Status, &j.Result, &j.Attempts, &j.LastError, &j.CreatedAt, &j.UpdatedAt); err != nil {
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
+
+// jobsHandler lists recent jobs for status inspection.
+func jobsHandler(w http.ResponseWriter, r *http.Request) {
+	jobs, err := jobQueue.List(100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobs)
+}
+
+// jobStatusHandler returns a single job by ID.
+func jobStatusHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.ParseInt(vars["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+
+	job, err := jobQueue.Get(id)
+	if err != nil {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
@@ -4,74 +4,83 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
-	"net/url"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/antmusumba/movielab/pkg/cache"
+	"github.com/antmusumba/movielab/pkg/omdb"
+	"github.com/antmusumba/movielab/pkg/ratelimit"
+	"github.com/antmusumba/movielab/pkg/tmdb"
+	"github.com/antmusumba/movielab/pkg/youtube"
+)
+
+// tmdbRateLimit mirrors TMDB's documented 40 requests per 10 seconds limit.
+const (
+	tmdbRateLimitRequests = 40
+	tmdbRateLimitWindow   = 10 * time.Second
 )
 
 // Movie represents a movie or TV show from TMDB
 type Movie struct {
-	ID          int     `json:"id"`
-	Title       string  `json:"title"`
-	Overview    string  `json:"overview"`
-	PosterPath  string  `json:"poster_path"`
-	ReleaseDate string  `json:"release_date"`
-	Rating      float64 `json:"rating"`
-	Genre       string  `json:"genre"`
-	Type        string  `json:"type"` // movie or tv
+	ID          int      `json:"id"`
+	Title       string   `json:"title"`
+	Overview    string   `json:"overview"`
+	PosterPath  string   `json:"poster_path"`
+	ReleaseDate string   `json:"release_date"`
+	Rating      float64  `json:"rating"`
+	Ratings     []Rating `json:"ratings,omitempty"`
+	Genre       string   `json:"genre"`
+	Type        string   `json:"type"` // movie or tv
 }
 
-// WatchlistItem represents an item in the user's watchlist
+// WatchlistItem represents an item in the user's watchlist. SeasonNumber and
+// EpisodeNumber are only meaningful when MediaType is "tv" and the item
+// tracks progress on a single episode rather than the whole show;
+// WatchedEpisodes holds the show-level per-episode progress as a JSON array
+// of "season:episode" strings.
 type WatchlistItem struct {
-	ID         int    `json:"id"`
-	MovieID    int    `json:"movie_id"`
-	Title      string `json:"title"`
-	Watched    bool   `json:"watched"`
-	AddedAt    string `json:"added_at"`
-	PosterPath string `json:"poster_path"`
+	ID              int    `json:"id"`
+	MovieID         int    `json:"movie_id"`
+	Title           string `json:"title"`
+	Watched         bool   `json:"watched"`
+	AddedAt         string `json:"added_at"`
+	PosterPath      string `json:"poster_path"`
+	MediaType       string `json:"media_type"`
+	SeasonNumber    *int   `json:"season_number,omitempty"`
+	EpisodeNumber   *int   `json:"episode_number,omitempty"`
+	WatchedEpisodes string `json:"watched_episodes,omitempty"`
 }
 
-// TMDBResponse represents the structure of a TMDB API response
-type TMDBResponse struct {
-	Results []struct {
-		ID           int     `json:"id"`
-		Title        string  `json:"title"`
-		Name         string  `json:"name"`
-		Overview     string  `json:"overview"`
-		PosterPath   string  `json:"poster_path"`
-		ReleaseDate  string  `json:"release_date"`
-		FirstAirDate string  `json:"first_air_date"`
-		VoteAverage  float64 `json:"vote_average"`
-		GenreIDs     []int   `json:"genre_ids"`
-		MediaType    string  `json:"media_type"`
-	} `json:"results"`
-	TotalPages int `json:"total_pages"`
-}
-
-// OMDBResponse represents the structure of an OMDB API response
-type OMDBResponse struct {
-	Title          string `json:"Title"`
-	Year           string `json:"Year"`
-	Plot           string `json:"Plot"`
-	IMDBRating     string `json:"imdbRating"`
-	RottenTomatoes string `json:"Ratings"`
+// MovieDetail is the enriched document cached and served by movieDetailHandler,
+// combining typed TMDB details with typed OMDB ratings.
+type MovieDetail struct {
+	*tmdb.MovieDetails
+	OMDB    *omdb.MovieResponse `json:"omdb,omitempty"`
+	Ratings []Rating            `json:"ratings,omitempty"`
 }
 
 var (
-	db         *sql.DB
-	tmdbAPIKey string
-	omdbAPIKey string
+	db            *sql.DB
+	jobQueue      *JobQueue
+	tmdbClient    *tmdb.Client
+	omdbClient    *omdb.Client
+	youtubeClient *youtube.Client
+	tmdbAPIKey    string
+	omdbAPIKey    string
 	youtubeAPIKey string
 )
 
+// enrichmentWorkerCount is the number of goroutines consuming the job queue.
+const enrichmentWorkerCount = 4
+
 // init loads API keys from environment variables
 func init() {
 	tmdbAPIKey = os.Getenv("TMDB_API_KEY")
@@ -106,7 +115,10 @@ func initDB() {
 		watched BOOLEAN DEFAULT FALSE,
 		added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		poster_path TEXT,
-		media_type TEXT DEFAULT 'movie'
+		media_type TEXT DEFAULT 'movie',
+		season_number INTEGER,
+		episode_number INTEGER,
+		watched_episodes TEXT DEFAULT '[]'
 	);
 	
 	CREATE TABLE IF NOT EXISTS user_preferences (
@@ -114,29 +126,90 @@ func initDB() {
 		preference_key TEXT UNIQUE NOT NULL,
 		preference_value TEXT NOT NULL
 	);
+
+	CREATE TABLE IF NOT EXISTS jobs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		type TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		result TEXT DEFAULT '',
+		attempts INTEGER DEFAULT 0,
+		last_error TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS movie_cache (
+		movie_id TEXT PRIMARY KEY,
+		data TEXT NOT NULL,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
 	`
 
 	_, err = db.Exec(createTables)
 	if err != nil {
 		log.Fatal(err)
 	}
+
+	migrateWatchlistColumns()
+}
+
+// migrateWatchlistColumns adds columns introduced after the original
+// watchlist table to databases created before they existed. CREATE TABLE IF
+// NOT EXISTS only applies to brand-new databases, so upgrades need an
+// explicit ALTER TABLE; SQLite errors on a column that already exists, which
+// these calls ignore since that just means the migration already ran.
+func migrateWatchlistColumns() {
+	migrations := []string{
+		"ALTER TABLE watchlist ADD COLUMN media_type TEXT DEFAULT 'movie'",
+		"ALTER TABLE watchlist ADD COLUMN season_number INTEGER",
+		"ALTER TABLE watchlist ADD COLUMN episode_number INTEGER",
+		"ALTER TABLE watchlist ADD COLUMN watched_episodes TEXT DEFAULT '[]'",
+	}
+	for _, migration := range migrations {
+		db.Exec(migration)
+	}
 }
 
 func main() {
 	initDB()
 	defer db.Close()
 
+	apiCache, err := cache.NewFileStore(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+	tmdbLimiter := ratelimit.New(tmdbRateLimitRequests, tmdbRateLimitWindow)
+	tmdbClient = tmdb.NewClient(tmdbAPIKey, tmdbLimiter, apiCache)
+	omdbClient = omdb.NewClient(omdbAPIKey, apiCache)
+	youtubeClient = youtube.NewClient(youtubeAPIKey, apiCache)
+
+	jobQueue = NewJobQueue(db)
+	jobQueue.Register(JobTypeFetchTMDBDetails, fetchTMDBDetailsJob)
+	jobQueue.Register(JobTypeFetchOMDBRatings, fetchOMDBRatingsJob)
+	jobQueue.Register(JobTypeFetchYouTubeTrailer, fetchYouTubeTrailerJob)
+	jobQueue.Register(JobTypeFetchRecommendations, fetchRecommendationsJob)
+	jobQueue.StartWorkers(enrichmentWorkerCount)
+
 	r := mux.NewRouter()
 
 	// API Routes
 	r.HandleFunc("/api/search", searchHandler).Methods("GET")
 	r.HandleFunc("/api/trending", trendingHandler).Methods("GET")
 	r.HandleFunc("/api/movie/{id}", movieDetailHandler).Methods("GET")
+	r.HandleFunc("/api/ratings/{id}", ratingsHandler).Methods("GET")
+	r.HandleFunc("/api/tv/{id}", tvShowHandler).Methods("GET")
+	r.HandleFunc("/api/tv/{id}/season/{season}", tvSeasonHandler).Methods("GET")
+	r.HandleFunc("/api/tv/{id}/season/{season}/episode/{episode}", tvEpisodeHandler).Methods("GET")
 	r.HandleFunc("/api/watchlist", watchlistHandler).Methods("GET", "POST", "DELETE")
+	r.HandleFunc("/api/watchlist/export", exportWatchlistHandler).Methods("GET")
+	r.HandleFunc("/api/watchlist/import", importWatchlistHandler).Methods("POST")
 	r.HandleFunc("/api/watchlist/{id}", watchlistItemHandler).Methods("PUT", "DELETE")
 	r.HandleFunc("/api/recommendations", recommendationsHandler).Methods("GET")
 	r.HandleFunc("/api/trailer", youtubeTrailerHandler).Methods("GET")
 	r.HandleFunc("/api/trending-trailers", trendingTrailersHandler).Methods("GET")
+	r.HandleFunc("/api/jobs", jobsHandler).Methods("GET")
+	r.HandleFunc("/api/jobs/{id}", jobStatusHandler).Methods("GET")
 
 	// Serve static files
 	r.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
@@ -171,28 +244,17 @@ func searchHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Search TMDB API
-	url := fmt.Sprintf("https://api.themoviedb.org/3/search/multi?api_key=%s&query=%s&page=%s", tmdbAPIKey, query, page)
-
-	resp, err := http.Get(url)
+	pageNum, err := strconv.Atoi(page)
 	if err != nil {
-		// http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		pageNum = 1
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	tmdbResp, err := tmdbClient.SearchMulti(query, pageNum)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	var tmdbResp TMDBResponse
-	if err := json.Unmarshal(body, &tmdbResp); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
 	// Transform TMDB results to Movie structs
 	var movies []Movie
 	for _, result := range tmdbResp.Results {
@@ -220,9 +282,25 @@ func searchHandler(w http.ResponseWriter, r *http.Request) {
 			Rating:      result.VoteAverage,
 			Type:        mediaType,
 		}
+		if mediaType == "movie" {
+			movie.Ratings = cachedRatingsFor(movie.ID)
+		}
 		movies = append(movies, movie)
 	}
 
+	// Pre-warm the movie_cache for the top results so a follow-up
+	// movieDetailHandler call is served from cache instead of a cold fetch.
+	prewarmCount := 5
+	if len(movies) < prewarmCount {
+		prewarmCount = len(movies)
+	}
+	for _, movie := range movies[:prewarmCount] {
+		if movie.Type != "movie" {
+			continue
+		}
+		jobQueue.Enqueue(JobTypeFetchTMDBDetails, tmdbDetailsPayload{MovieID: strconv.Itoa(movie.ID)})
+	}
+
 	// Respond with search results
 	response := map[string]interface{}{
 		"results":     movies,
@@ -241,26 +319,11 @@ func trendingHandler(w http.ResponseWriter, r *http.Request) {
 		mediaType = "movie"
 	}
 
-	url := fmt.Sprintf("https://api.themoviedb.org/3/trending/%s/week?api_key=%s", mediaType, tmdbAPIKey)
-
-	resp, err := http.Get(url)
+	tmdbResp, err := tmdbClient.Trending(mediaType)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	var tmdbResp TMDBResponse
-	if err := json.Unmarshal(body, &tmdbResp); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
 
 	var movies []Movie
 	for _, result := range tmdbResp.Results {
@@ -283,6 +346,9 @@ func trendingHandler(w http.ResponseWriter, r *http.Request) {
 			Rating:      result.VoteAverage,
 			Type:        mediaType,
 		}
+		if mediaType == "movie" {
+			movie.Ratings = cachedRatingsFor(movie.ID)
+		}
 		movies = append(movies, movie)
 	}
 
@@ -290,50 +356,163 @@ func trendingHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(movies)
 }
 
-// movieDetailHandler returns detailed info for a specific movie, merging TMDB and OMDB data
-func movieDetailHandler(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	movieID := vars["id"]
+// tmdbDetailsPayload is the job payload for JobTypeFetchTMDBDetails.
+type tmdbDetailsPayload struct {
+	MovieID string `json:"movie_id"`
+}
+
+// omdbRatingsPayload is the job payload for JobTypeFetchOMDBRatings.
+type omdbRatingsPayload struct {
+	MovieID string `json:"movie_id"`
+	Title   string `json:"title"`
+	Year    string `json:"year"`
+}
 
-	// Get TMDB details
-	tmdbURL := fmt.Sprintf("https://api.themoviedb.org/3/movie/%s?api_key=%s&append_to_response=credits", movieID, tmdbAPIKey)
+// youtubeTrailerPayload is the job payload for JobTypeFetchYouTubeTrailer.
+type youtubeTrailerPayload struct {
+	Title string `json:"title"`
+	Year  string `json:"year"`
+}
+
+// releaseYear extracts the 4-digit year from a TMDB release_date field.
+func releaseYear(releaseDate string) string {
+	if len(releaseDate) >= 4 {
+		return releaseDate[:4]
+	}
+	return ""
+}
 
-	resp, err := http.Get(tmdbURL)
+// getMovieCache returns the last enriched document stored for movieID.
+func getMovieCache(movieID string) (*MovieDetail, bool) {
+	var data string
+	err := db.QueryRow("SELECT data FROM movie_cache WHERE movie_id = ?", movieID).Scan(&data)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return nil, false
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	var detail MovieDetail
+	if json.Unmarshal([]byte(data), &detail) != nil {
+		return nil, false
+	}
+	return &detail, true
+}
+
+// movieCacheLocks serializes the read-modify-write merge fetchTMDBDetailsJob
+// and fetchOMDBRatingsJob each do against movie_cache, keyed per movie ID so
+// the two jobs can't interleave and clobber each other's half of the
+// document; unrelated movies still merge concurrently.
+var movieCacheLocks sync.Map // map[string]*sync.Mutex
+
+func lockMovieCache(movieID string) func() {
+	value, _ := movieCacheLocks.LoadOrStore(movieID, &sync.Mutex{})
+	mu := value.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// upsertMovieCache stores (or replaces) the enriched document for movieID.
+func upsertMovieCache(movieID string, detail *MovieDetail) error {
+	data, err := json.Marshal(detail)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return err
 	}
+	_, err = db.Exec(
+		"INSERT INTO movie_cache (movie_id, data, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP) ON CONFLICT(movie_id) DO UPDATE SET data = excluded.data, updated_at = CURRENT_TIMESTAMP",
+		movieID, string(data),
+	)
+	return err
+}
 
-	var movieDetail map[string]interface{}
-	if err := json.Unmarshal(body, &movieDetail); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+// fetchTMDBDetailsJob fetches TMDB details and merges them into the cached
+// document for the movie, preserving any OMDB data already present.
+func fetchTMDBDetailsJob(payload string) (string, error) {
+	var p tmdbDetailsPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return "", err
+	}
+
+	details, err := tmdbClient.GetMovieDetails(p.MovieID, "")
+	if err != nil {
+		return "", err
+	}
+
+	unlock := lockMovieCache(p.MovieID)
+	defer unlock()
+
+	merged := &MovieDetail{MovieDetails: details}
+	if cached, ok := getMovieCache(p.MovieID); ok {
+		merged.OMDB = cached.OMDB
+	}
+	merged.Ratings = buildRatings(merged.MovieDetails, merged.OMDB)
+
+	if err := upsertMovieCache(p.MovieID, merged); err != nil {
+		return "", err
+	}
+
+	result, err := json.Marshal(merged)
+	return string(result), err
+}
+
+// fetchOMDBRatingsJob fetches OMDB ratings and merges them into the cached
+// document for the movie.
+func fetchOMDBRatingsJob(payload string) (string, error) {
+	var p omdbRatingsPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return "", err
+	}
+
+	omdbData, err := omdbClient.GetByTitle(p.Title, p.Year)
+	if err != nil {
+		return "", err
+	}
+
+	unlock := lockMovieCache(p.MovieID)
+	defer unlock()
+
+	merged := &MovieDetail{OMDB: omdbData}
+	if cached, ok := getMovieCache(p.MovieID); ok {
+		merged.MovieDetails = cached.MovieDetails
+	}
+	merged.Ratings = buildRatings(merged.MovieDetails, merged.OMDB)
+
+	if err := upsertMovieCache(p.MovieID, merged); err != nil {
+		return "", err
+	}
+
+	result, err := json.Marshal(merged)
+	return string(result), err
+}
+
+// movieDetailHandler returns detailed info for a specific movie, merging TMDB and OMDB data.
+// A cached document is served immediately while a background job refreshes it;
+// on a cold cache the fetch runs inline so the first request still gets data.
+func movieDetailHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	movieID := vars["id"]
+
+	if cached, ok := getMovieCache(movieID); ok {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cached)
+
+		jobQueue.Enqueue(JobTypeFetchTMDBDetails, tmdbDetailsPayload{MovieID: movieID})
+		if cached.MovieDetails != nil && cached.Title != "" {
+			jobQueue.Enqueue(JobTypeFetchOMDBRatings, omdbRatingsPayload{MovieID: movieID, Title: cached.Title, Year: releaseYear(cached.ReleaseDate)})
+		}
 		return
 	}
 
-	// Get OMDB details for additional ratings
-	title := movieDetail["title"].(string)
-	year := ""
-	if releaseDate, ok := movieDetail["release_date"].(string); ok && len(releaseDate) >= 4 {
-		year = releaseDate[:4]
+	result, err := jobQueue.Run(JobTypeFetchTMDBDetails, tmdbDetailsPayload{MovieID: movieID})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	omdbURL := fmt.Sprintf("http://www.omdbapi.com/?t=%s&y=%s&apikey=%s", title, year, omdbAPIKey)
+	var movieDetail MovieDetail
+	json.Unmarshal([]byte(result), &movieDetail)
 
-	omdbResp, err := http.Get(omdbURL)
-	if err == nil {
-		defer omdbResp.Body.Close()
-		omdbBody, _ := io.ReadAll(omdbResp.Body)
-		var omdbData map[string]interface{}
-		if json.Unmarshal(omdbBody, &omdbData) == nil {
-			// Merge OMDB data with TMDB data
-			movieDetail["omdb"] = omdbData
+	if movieDetail.MovieDetails != nil && movieDetail.Title != "" {
+		if omdbResult, err := jobQueue.Run(JobTypeFetchOMDBRatings, omdbRatingsPayload{MovieID: movieID, Title: movieDetail.Title, Year: releaseYear(movieDetail.ReleaseDate)}); err == nil {
+			json.Unmarshal([]byte(omdbResult), &movieDetail)
 		}
 	}
 
@@ -346,7 +525,7 @@ func watchlistHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case "GET":
 		// Return all watchlist items
-		rows, err := db.Query("SELECT id, movie_id, title, watched, added_at, poster_path FROM watchlist ORDER BY added_at DESC")
+		rows, err := db.Query("SELECT id, movie_id, title, watched, added_at, poster_path, media_type, season_number, episode_number, watched_episodes FROM watchlist ORDER BY added_at DESC")
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -356,7 +535,8 @@ func watchlistHandler(w http.ResponseWriter, r *http.Request) {
 		var items []WatchlistItem
 		for rows.Next() {
 			var item WatchlistItem
-			err := rows.Scan(&item.ID, &item.MovieID, &item.Title, &item.Watched, &item.AddedAt, &item.PosterPath)
+			err := rows.Scan(&item.ID, &item.MovieID, &item.Title, &item.Watched, &item.AddedAt, &item.PosterPath,
+				&item.MediaType, &item.SeasonNumber, &item.EpisodeNumber, &item.WatchedEpisodes)
 			if err != nil {
 				continue
 			}
@@ -373,9 +553,13 @@ func watchlistHandler(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		if item.MediaType == "" {
+			item.MediaType = "movie"
+		}
 
-		result, err := db.Exec("INSERT INTO watchlist (movie_id, title, poster_path) VALUES (?, ?, ?)",
-			item.MovieID, item.Title, item.PosterPath)
+		result, err := db.Exec(
+			"INSERT INTO watchlist (movie_id, title, poster_path, media_type, season_number, episode_number) VALUES (?, ?, ?, ?, ?, ?)",
+			item.MovieID, item.Title, item.PosterPath, item.MediaType, item.SeasonNumber, item.EpisodeNumber)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -384,6 +568,7 @@ func watchlistHandler(w http.ResponseWriter, r *http.Request) {
 		id, _ := result.LastInsertId()
 		item.ID = int(id)
 		item.AddedAt = time.Now().Format("2006-01-02 15:04:05")
+		invalidateGenreVector()
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(item)
@@ -395,6 +580,7 @@ func watchlistHandler(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		invalidateGenreVector()
 		w.WriteHeader(http.StatusOK)
 	}
 }
@@ -406,18 +592,27 @@ func watchlistItemHandler(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case "PUT":
-		// Update watched status of a watchlist item
+		// Update watched status (and, for TV shows, per-episode progress) of a watchlist item
 		var item WatchlistItem
 		if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		_, err := db.Exec("UPDATE watchlist SET watched = ? WHERE id = ?", item.Watched, id)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		if item.WatchedEpisodes != "" {
+			_, err := db.Exec("UPDATE watchlist SET watched = ?, watched_episodes = ? WHERE id = ?", item.Watched, item.WatchedEpisodes, id)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		} else {
+			_, err := db.Exec("UPDATE watchlist SET watched = ? WHERE id = ?", item.Watched, id)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
 		}
+		invalidateGenreVector()
 
 		w.WriteHeader(http.StatusOK)
 
@@ -428,74 +623,118 @@ func watchlistItemHandler(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		invalidateGenreVector()
 		w.WriteHeader(http.StatusOK)
 	}
 }
 
-// recommendationsHandler returns movie recommendations based on the user's watchlist
+// recommendationsHandler returns personalized recommendations built from the
+// user's whole watchlist. See recommendations.go for the scoring engine;
+// this falls back to a single-seed TV recommendation, then to trending,
+// when the watchlist has no movie entries to score against.
 func recommendationsHandler(w http.ResponseWriter, r *http.Request) {
-	// Get user's watchlist to generate recommendations
-	rows, err := db.Query("SELECT movie_id FROM watchlist LIMIT 5")
+	rows, err := db.Query("SELECT movie_id, watched, media_type FROM watchlist")
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	defer rows.Close()
 
-	var movieIDs []string
+	var entries []watchlistEntry
 	for rows.Next() {
-		var movieID int
-		if err := rows.Scan(&movieID); err != nil {
+		var e watchlistEntry
+		if err := rows.Scan(&e.MovieID, &e.Watched, &e.MediaType); err != nil {
 			continue
 		}
-		movieIDs = append(movieIDs, strconv.Itoa(movieID))
+		entries = append(entries, e)
 	}
 
-	if len(movieIDs) == 0 {
-		// If no watchlist, return trending movies
-		trendingHandler(w, r)
-		return
+	var movieEntries []watchlistEntry
+	var tvEntry *watchlistEntry
+	for i := range entries {
+		if entries[i].MediaType == "tv" {
+			if tvEntry == nil {
+				tvEntry = &entries[i]
+			}
+			continue
+		}
+		movieEntries = append(movieEntries, entries[i])
 	}
 
-	// Get recommendations based on first movie in watchlist
-	movieID := movieIDs[0]
-	url := fmt.Sprintf("https://api.themoviedb.org/3/movie/%s/recommendations?api_key=%s", movieID, tmdbAPIKey)
-
-	resp, err := http.Get(url)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if len(movieEntries) > 0 {
+		movies, err := scoredMovieRecommendations(movieEntries)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(movies)
 		return
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	if tvEntry != nil {
+		result, err := jobQueue.Run(JobTypeFetchRecommendations, recommendationsPayload{MovieID: strconv.Itoa(tvEntry.MovieID)})
+		if err == nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(result))
+			return
+		}
 	}
 
-	var tmdbResp TMDBResponse
-	if err := json.Unmarshal(body, &tmdbResp); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	// Empty watchlist, or nothing we could fetch recommendations for.
+	trendingHandler(w, r)
+}
+
+// recommendationsPayload is the job payload for JobTypeFetchRecommendations.
+type recommendationsPayload struct {
+	MovieID string `json:"movie_id"`
+}
+
+// fetchRecommendationsJob fetches TMDB recommendations for a single TV show,
+// used as a fallback when the watchlist has no movies to build a genre
+// vector from.
+func fetchRecommendationsJob(payload string) (string, error) {
+	var p recommendationsPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return "", err
 	}
 
-	var movies []Movie
+	tmdbResp, err := tmdbClient.TVRecommendations(p.MovieID)
+	if err != nil {
+		return "", err
+	}
+
+	var shows []Movie
 	for _, result := range tmdbResp.Results {
-		movie := Movie{
+		shows = append(shows, Movie{
 			ID:          result.ID,
-			Title:       result.Title,
+			Title:       result.Name,
 			Overview:    result.Overview,
 			PosterPath:  result.PosterPath,
-			ReleaseDate: result.ReleaseDate,
+			ReleaseDate: result.FirstAirDate,
 			Rating:      result.VoteAverage,
-			Type:        "movie",
-		}
-		movies = append(movies, movie)
+			Type:        "tv",
+		})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(movies)
+	out, err := json.Marshal(shows)
+	return string(out), err
+}
+
+// fetchYouTubeTrailerJob looks up a single trailer's videoId.
+func fetchYouTubeTrailerJob(payload string) (string, error) {
+	var p youtubeTrailerPayload
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return "", err
+	}
+
+	videoID, err := youtubeClient.SearchTrailer(p.Title, p.Year)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(map[string]string{"videoId": videoID})
+	return string(out), err
 }
 
 // youtubeTrailerHandler searches YouTube for the official trailer and returns the videoId
@@ -506,92 +745,86 @@ func youtubeTrailerHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "title is required", http.StatusBadRequest)
 		return
 	}
-	query := fmt.Sprintf("%s %s official trailer", title, year)
-	apiKey := youtubeAPIKey
-	ytURL := fmt.Sprintf("https://www.googleapis.com/youtube/v3/search?part=snippet&q=%s&type=video&key=%s&maxResults=1", url.QueryEscape(query), apiKey)
-	resp, err := http.Get(ytURL)
+
+	result, err := jobQueue.Run(JobTypeFetchYouTubeTrailer, youtubeTrailerPayload{Title: title, Year: year})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	defer resp.Body.Close()
-	var ytResp map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&ytResp); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	items, ok := ytResp["items"].([]interface{})
-	if !ok || len(items) == 0 {
-		w.WriteHeader(http.StatusNoContent)
-		return
-	}
-	id, ok := items[0].(map[string]interface{})["id"].(map[string]interface{})["videoId"].(string)
-	if !ok {
+
+	var parsed map[string]string
+	json.Unmarshal([]byte(result), &parsed)
+	if parsed["videoId"] == "" {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
-	json.NewEncoder(w).Encode(map[string]string{"videoId": id})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(parsed)
+}
+
+// trailerResult pairs a trending movie with its resolved trailer, keeping
+// results ordered even though the lookups run concurrently.
+type trailerResult struct {
+	index int
+	data  map[string]interface{}
 }
 
-// trendingTrailersHandler returns the top 4 trending movies with their YouTube trailer videoIds
+// trendingTrailersHandler returns the top 4 trending movies with their YouTube trailer videoIds.
+// The YouTube lookups run concurrently (and through the job queue, so each is
+// retried independently) instead of blocking on 4 serial requests.
 func trendingTrailersHandler(w http.ResponseWriter, r *http.Request) {
-	// Fetch trending movies from TMDB
-	tmdbURL := fmt.Sprintf("https://api.themoviedb.org/3/trending/movie/week?api_key=%s&page=1", tmdbAPIKey)
-	resp, err := http.Get(tmdbURL)
+	tmdbResp, err := tmdbClient.Trending("movie")
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	defer resp.Body.Close()
-	var tmdbResp TMDBResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tmdbResp); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
 
-	results := []map[string]interface{}{}
 	max := 4
 	if len(tmdbResp.Results) < max {
 		max = len(tmdbResp.Results)
 	}
+
+	resultCh := make(chan trailerResult, max)
 	for i := 0; i < max; i++ {
-		movie := tmdbResp.Results[i]
-		title := movie.Title
-		if title == "" {
-			title = movie.Name
-		}
-		releaseDate := movie.ReleaseDate
-		if releaseDate == "" {
-			releaseDate = movie.FirstAirDate
-		}
-		// Search YouTube for trailer
-		query := fmt.Sprintf("%s %s official trailer", title, releaseDate[:4])
-		ytURL := fmt.Sprintf("https://www.googleapis.com/youtube/v3/search?part=snippet&q=%s&type=video&key=%s&maxResults=1", url.QueryEscape(query), youtubeAPIKey)
-		ytResp, err := http.Get(ytURL)
-		if err != nil {
-			continue
-		}
-		var ytData map[string]interface{}
-		if err := json.NewDecoder(ytResp.Body).Decode(&ytData); err != nil {
-			ytResp.Body.Close()
-			continue
-		}
-		ytResp.Body.Close()
-		videoId := ""
-		if items, ok := ytData["items"].([]interface{}); ok && len(items) > 0 {
-			id, ok := items[0].(map[string]interface{})["id"].(map[string]interface{})["videoId"].(string)
-			if ok {
-				videoId = id
+		go func(i int) {
+			movie := tmdbResp.Results[i]
+			title := movie.Title
+			if title == "" {
+				title = movie.Name
 			}
-		}
-		results = append(results, map[string]interface{}{
-			"title":        title,
-			"poster_path":  movie.PosterPath,
-			"videoId":      videoId,
-			"release_date": releaseDate,
-			"overview":     movie.Overview,
-		})
+			releaseDate := movie.ReleaseDate
+			if releaseDate == "" {
+				releaseDate = movie.FirstAirDate
+			}
+			year := ""
+			if len(releaseDate) >= 4 {
+				year = releaseDate[:4]
+			}
+
+			videoId := ""
+			if out, err := jobQueue.Run(JobTypeFetchYouTubeTrailer, youtubeTrailerPayload{Title: title, Year: year}); err == nil {
+				var parsed map[string]string
+				json.Unmarshal([]byte(out), &parsed)
+				videoId = parsed["videoId"]
+			}
+
+			resultCh <- trailerResult{index: i, data: map[string]interface{}{
+				"title":        title,
+				"poster_path":  movie.PosterPath,
+				"videoId":      videoId,
+				"release_date": releaseDate,
+				"overview":     movie.Overview,
+			}}
+		}(i)
 	}
+
+	results := make([]map[string]interface{}, max)
+	for i := 0; i < max; i++ {
+		res := <-resultCh
+		results[res.index] = res.data
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(results)
 }
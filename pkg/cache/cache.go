@@ -0,0 +1,61 @@
+// Package cache provides a small TTL-based cache abstraction shared by the
+// TMDB, OMDB and YouTube clients so repeated lookups for the same movie
+// don't hit the upstream API again.
+package cache
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Cache stores opaque string values under a key for a limited time.
+type Cache interface {
+	// Get returns the stored value for key and whether it is still valid.
+	Get(key string) (string, bool)
+	// Set stores value under key for the given ttl.
+	Set(key string, value string, ttl time.Duration) error
+}
+
+// FileStore is the default Cache implementation, backed by a SQLite table
+// in the application's existing database.
+type FileStore struct {
+	db *sql.DB
+}
+
+// NewFileStore returns a FileStore backed by db, creating its table if needed.
+func NewFileStore(db *sql.DB) (*FileStore, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS api_cache (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			expires_at DATETIME NOT NULL
+		);
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return &FileStore{db: db}, nil
+}
+
+// Get implements Cache.
+func (f *FileStore) Get(key string) (string, bool) {
+	var value string
+	var expiresAt time.Time
+	err := f.db.QueryRow("SELECT value, expires_at FROM api_cache WHERE key = ?", key).Scan(&value, &expiresAt)
+	if err != nil {
+		return "", false
+	}
+	if time.Now().After(expiresAt) {
+		return "", false
+	}
+	return value, true
+}
+
+// Set implements Cache.
+func (f *FileStore) Set(key string, value string, ttl time.Duration) error {
+	_, err := f.db.Exec(
+		"INSERT INTO api_cache (key, value, expires_at) VALUES (?, ?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value, expires_at = excluded.expires_at",
+		key, value, time.Now().Add(ttl),
+	)
+	return err
+}
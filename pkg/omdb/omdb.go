@@ -0,0 +1,104 @@
+// Package omdb is a typed client for the OMDB API, used for IMDb/Rotten
+// Tomatoes/Metacritic ratings that TMDB doesn't provide.
+package omdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/antmusumba/movielab/pkg/cache"
+)
+
+const baseURL = "http://www.omdbapi.com/"
+
+// lookupTTL is how long an OMDB lookup is cached before being refetched.
+const lookupTTL = 24 * time.Hour
+
+// Rating is one entry of OMDB's Ratings array, e.g. Rotten Tomatoes or
+// Metacritic. OMDB returns this as a list of {Source, Value} objects, not
+// the single string the API docs' top-level fields might suggest.
+type Rating struct {
+	Source string `json:"Source"`
+	Value  string `json:"Value"`
+}
+
+// MovieResponse is the response of the "t="-by-title lookup.
+type MovieResponse struct {
+	Title      string   `json:"Title"`
+	Year       string   `json:"Year"`
+	Plot       string   `json:"Plot"`
+	IMDBRating string   `json:"imdbRating"`
+	Metascore  string   `json:"Metascore"`
+	Ratings    []Rating `json:"Ratings"`
+	Response   string   `json:"Response"`
+	Error      string   `json:"Error"`
+}
+
+// Client calls the OMDB API.
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+	cache      cache.Cache
+}
+
+// NewClient returns a Client. c may be nil to disable caching.
+func NewClient(apiKey string, c cache.Cache) *Client {
+	return &Client{apiKey: apiKey, httpClient: http.DefaultClient, cache: c}
+}
+
+// GetByTitle looks up a movie by title and release year, keyed in cache as
+// com.omdb.title.<title>.<year>.
+func (c *Client) GetByTitle(title, year string) (*MovieResponse, error) {
+	key := fmt.Sprintf("com.omdb.title.%s.%s", title, year)
+
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(key); ok {
+			var res MovieResponse
+			if json.Unmarshal([]byte(cached), &res) == nil {
+				return &res, nil
+			}
+		}
+	}
+
+	params := url.Values{
+		"t":      {title},
+		"y":      {year},
+		"apikey": {c.apiKey},
+	}
+
+	resp, err := c.httpClient.Get(baseURL + "?" + params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("omdb: lookup returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var res MovieResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+
+	if res.Response == "False" {
+		return nil, fmt.Errorf("omdb: %s", res.Error)
+	}
+
+	if c.cache != nil {
+		if cached, err := json.Marshal(res); err == nil {
+			c.cache.Set(key, string(cached), lookupTTL)
+		}
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,50 @@
+// Package ratelimit provides a simple sliding-window limiter used to keep
+// outbound calls to third-party APIs under their published rate limits.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter serializes calls so that no more than n occur within per.
+type Limiter struct {
+	mu   sync.Mutex
+	n    int
+	per  time.Duration
+	hits []time.Time
+}
+
+// New returns a Limiter allowing n calls per the given window.
+func New(n int, per time.Duration) *Limiter {
+	return &Limiter{n: n, per: per}
+}
+
+// Wait blocks until a call is allowed under the limit, then records it.
+func (l *Limiter) Wait() {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		cutoff := now.Add(-l.per)
+
+		kept := l.hits[:0]
+		for _, t := range l.hits {
+			if t.After(cutoff) {
+				kept = append(kept, t)
+			}
+		}
+		l.hits = kept
+
+		if len(l.hits) < l.n {
+			l.hits = append(l.hits, now)
+			l.mu.Unlock()
+			return
+		}
+
+		wait := l.hits[0].Add(l.per).Sub(now)
+		l.mu.Unlock()
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+}
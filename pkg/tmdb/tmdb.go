@@ -0,0 +1,364 @@
+// Package tmdb is a typed client for the subset of The Movie Database API
+// movielab uses: search, trending, movie details and recommendations.
+package tmdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antmusumba/movielab/pkg/cache"
+	"github.com/antmusumba/movielab/pkg/ratelimit"
+)
+
+const baseURL = "https://api.themoviedb.org/3"
+
+// detailTTL is how long a movie details response is cached before it's
+// considered stale and refetched.
+const detailTTL = 24 * time.Hour
+
+// Genre is a TMDB genre reference.
+type Genre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ProductionCompany is a studio or company credited on a movie.
+type ProductionCompany struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	LogoPath      string `json:"logo_path"`
+	OriginCountry string `json:"origin_country"`
+}
+
+// Collection is the franchise/collection a movie belongs to, if any.
+type Collection struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	PosterPath   string `json:"poster_path"`
+	BackdropPath string `json:"backdrop_path"`
+}
+
+// MovieDetails is the response of GET /movie/{id}.
+type MovieDetails struct {
+	ID                  int                 `json:"id"`
+	Title               string              `json:"title"`
+	Overview            string              `json:"overview"`
+	PosterPath          string              `json:"poster_path"`
+	ReleaseDate         string              `json:"release_date"`
+	VoteAverage         float64             `json:"vote_average"`
+	Genres              []Genre             `json:"genres"`
+	ProductionCompanies []ProductionCompany `json:"production_companies"`
+	IMDBID              string              `json:"imdb_id"`
+	BelongsToCollection *Collection         `json:"belongs_to_collection"`
+}
+
+// SearchResult is one entry of a search, trending or recommendations response.
+type SearchResult struct {
+	ID           int     `json:"id"`
+	Title        string  `json:"title"`
+	Name         string  `json:"name"`
+	Overview     string  `json:"overview"`
+	PosterPath   string  `json:"poster_path"`
+	ReleaseDate  string  `json:"release_date"`
+	FirstAirDate string  `json:"first_air_date"`
+	VoteAverage  float64 `json:"vote_average"`
+	GenreIDs     []int   `json:"genre_ids"`
+	MediaType    string  `json:"media_type"`
+}
+
+// SearchResponse is the paginated envelope TMDB returns for list endpoints.
+type SearchResponse struct {
+	Results    []SearchResult `json:"results"`
+	TotalPages int            `json:"total_pages"`
+}
+
+// tvTTL is how long TV show/season/episode responses are cached.
+const tvTTL = 24 * time.Hour
+
+// CastMember is a credited or guest-starring actor.
+type CastMember struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Character   string `json:"character"`
+	ProfilePath string `json:"profile_path"`
+}
+
+// TVShow is the response of GET /tv/{id}.
+type TVShow struct {
+	ID               int     `json:"id"`
+	Name             string  `json:"name"`
+	Overview         string  `json:"overview"`
+	PosterPath       string  `json:"poster_path"`
+	FirstAirDate     string  `json:"first_air_date"`
+	VoteAverage      float64 `json:"vote_average"`
+	NumberOfSeasons  int     `json:"number_of_seasons"`
+	NumberOfEpisodes int     `json:"number_of_episodes"`
+	Genres           []Genre `json:"genres"`
+}
+
+// Season is the response of GET /tv/{id}/season/{n}.
+type Season struct {
+	ID           int       `json:"id"`
+	SeasonNumber int       `json:"season_number"`
+	Name         string    `json:"name"`
+	Overview     string    `json:"overview"`
+	AirDate      string    `json:"air_date"`
+	PosterPath   string    `json:"poster_path"`
+	Episodes     []Episode `json:"episodes"`
+}
+
+// Episode is the response of GET /tv/{id}/season/{n}/episode/{e}, and also
+// the shape of each entry in Season.Episodes.
+type Episode struct {
+	ID            int          `json:"id"`
+	EpisodeNumber int          `json:"episode_number"`
+	SeasonNumber  int          `json:"season_number"`
+	Name          string       `json:"name"`
+	Overview      string       `json:"overview"`
+	AirDate       string       `json:"air_date"`
+	StillPath     string       `json:"still_path"`
+	VoteAverage   float64      `json:"vote_average"`
+	GuestStars    []CastMember `json:"guest_stars"`
+}
+
+// Client calls the TMDB API, rate-limiting and caching as configured.
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+	limiter    *ratelimit.Limiter
+	cache      cache.Cache
+}
+
+// NewClient returns a Client. limiter and c may be nil to disable rate
+// limiting or caching respectively.
+func NewClient(apiKey string, limiter *ratelimit.Limiter, c cache.Cache) *Client {
+	return &Client{
+		apiKey:     apiKey,
+		httpClient: http.DefaultClient,
+		limiter:    limiter,
+		cache:      c,
+	}
+}
+
+func (c *Client) get(path string, params url.Values, out interface{}) error {
+	if c.limiter != nil {
+		c.limiter.Wait()
+	}
+
+	params = cloneValues(params)
+	params.Set("api_key", c.apiKey)
+
+	resp, err := c.httpClient.Get(fmt.Sprintf("%s%s?%s", baseURL, path, params.Encode()))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("tmdb: %s returned status %d", path, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+func cloneValues(v url.Values) url.Values {
+	out := url.Values{}
+	for k, vs := range v {
+		out[k] = append([]string(nil), vs...)
+	}
+	return out
+}
+
+// GetMovieDetails returns movie details for id, keyed in cache as
+// com.tmdb.movie.<id>.<lang>.
+func (c *Client) GetMovieDetails(id, lang string) (*MovieDetails, error) {
+	if lang == "" {
+		lang = "en-US"
+	}
+	key := fmt.Sprintf("com.tmdb.movie.%s.%s", id, lang)
+
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(key); ok {
+			var details MovieDetails
+			if json.Unmarshal([]byte(cached), &details) == nil {
+				return &details, nil
+			}
+		}
+	}
+
+	var details MovieDetails
+	err := c.get(fmt.Sprintf("/movie/%s", id), url.Values{
+		"language":           {lang},
+		"append_to_response": {"credits"},
+	}, &details)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		if body, err := json.Marshal(details); err == nil {
+			c.cache.Set(key, string(body), detailTTL)
+		}
+	}
+
+	return &details, nil
+}
+
+// SearchMulti searches movies, TV shows and people matching query.
+func (c *Client) SearchMulti(query string, page int) (*SearchResponse, error) {
+	var resp SearchResponse
+	err := c.get("/search/multi", url.Values{
+		"query": {query},
+		"page":  {fmt.Sprintf("%d", page)},
+	}, &resp)
+	return &resp, err
+}
+
+// Trending returns weekly trending results for mediaType ("movie" or "tv").
+func (c *Client) Trending(mediaType string) (*SearchResponse, error) {
+	var resp SearchResponse
+	err := c.get(fmt.Sprintf("/trending/%s/week", mediaType), nil, &resp)
+	return &resp, err
+}
+
+// Recommendations returns movies recommended alongside movieID.
+func (c *Client) Recommendations(movieID string) (*SearchResponse, error) {
+	var resp SearchResponse
+	err := c.get(fmt.Sprintf("/movie/%s/recommendations", movieID), nil, &resp)
+	return &resp, err
+}
+
+// Discover returns movies matching any of genreIDs with at least
+// minVoteCount votes, via GET /discover/movie.
+func (c *Client) Discover(genreIDs []int, minVoteCount int) (*SearchResponse, error) {
+	genres := make([]string, len(genreIDs))
+	for i, id := range genreIDs {
+		genres[i] = strconv.Itoa(id)
+	}
+
+	var resp SearchResponse
+	err := c.get("/discover/movie", url.Values{
+		"with_genres":    {strings.Join(genres, "|")},
+		"vote_count.gte": {strconv.Itoa(minVoteCount)},
+	}, &resp)
+	return &resp, err
+}
+
+// FindResponse is the response of GET /find/{external_id}.
+type FindResponse struct {
+	MovieResults []SearchResult `json:"movie_results"`
+	TVResults    []SearchResult `json:"tv_results"`
+}
+
+// Find resolves an ID from an external source (e.g. "imdb_id") to its TMDB
+// movie/TV entries, via GET /find/{externalID}?external_source=<source>.
+func (c *Client) Find(externalID, source string) (*FindResponse, error) {
+	var resp FindResponse
+	err := c.get(fmt.Sprintf("/find/%s", externalID), url.Values{
+		"external_source": {source},
+	}, &resp)
+	return &resp, err
+}
+
+// TVRecommendations returns TV shows recommended alongside tvID.
+func (c *Client) TVRecommendations(tvID string) (*SearchResponse, error) {
+	var resp SearchResponse
+	err := c.get(fmt.Sprintf("/tv/%s/recommendations", tvID), nil, &resp)
+	return &resp, err
+}
+
+// GetTVShow returns a TV show's details, cached as com.tmdb.tv.<id>.<lang>.
+func (c *Client) GetTVShow(id, lang string) (*TVShow, error) {
+	if lang == "" {
+		lang = "en-US"
+	}
+	key := fmt.Sprintf("com.tmdb.tv.%s.%s", id, lang)
+
+	var show TVShow
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(key); ok && json.Unmarshal([]byte(cached), &show) == nil {
+			return &show, nil
+		}
+	}
+
+	if err := c.get(fmt.Sprintf("/tv/%s", id), url.Values{"language": {lang}}, &show); err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		if body, err := json.Marshal(show); err == nil {
+			c.cache.Set(key, string(body), tvTTL)
+		}
+	}
+
+	return &show, nil
+}
+
+// GetSeason returns a season's episodes, cached as
+// com.tmdb.tv.<id>.season.<n>.<lang>.
+func (c *Client) GetSeason(id string, seasonNumber int, lang string) (*Season, error) {
+	if lang == "" {
+		lang = "en-US"
+	}
+	key := fmt.Sprintf("com.tmdb.tv.%s.season.%d.%s", id, seasonNumber, lang)
+
+	var season Season
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(key); ok && json.Unmarshal([]byte(cached), &season) == nil {
+			return &season, nil
+		}
+	}
+
+	path := fmt.Sprintf("/tv/%s/season/%d", id, seasonNumber)
+	if err := c.get(path, url.Values{"language": {lang}}, &season); err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		if body, err := json.Marshal(season); err == nil {
+			c.cache.Set(key, string(body), tvTTL)
+		}
+	}
+
+	return &season, nil
+}
+
+// GetEpisode returns a single episode, cached as
+// com.tmdb.tv.<id>.season.<n>.episode.<e>.<lang>.
+func (c *Client) GetEpisode(id string, seasonNumber, episodeNumber int, lang string) (*Episode, error) {
+	if lang == "" {
+		lang = "en-US"
+	}
+	key := fmt.Sprintf("com.tmdb.tv.%s.season.%d.episode.%d.%s", id, seasonNumber, episodeNumber, lang)
+
+	var episode Episode
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(key); ok && json.Unmarshal([]byte(cached), &episode) == nil {
+			return &episode, nil
+		}
+	}
+
+	path := fmt.Sprintf("/tv/%s/season/%d/episode/%d", id, seasonNumber, episodeNumber)
+	if err := c.get(path, url.Values{"language": {lang}}, &episode); err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		if body, err := json.Marshal(episode); err == nil {
+			c.cache.Set(key, string(body), tvTTL)
+		}
+	}
+
+	return &episode, nil
+}
@@ -0,0 +1,92 @@
+// Package youtube is a minimal typed client for the YouTube Data API's
+// search endpoint, used to resolve a movie's official trailer video.
+package youtube
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/antmusumba/movielab/pkg/cache"
+)
+
+const searchURL = "https://www.googleapis.com/youtube/v3/search"
+
+// trailerTTL is how long a resolved trailer videoId is cached.
+const trailerTTL = 7 * 24 * time.Hour
+
+type searchResponse struct {
+	Items []struct {
+		ID struct {
+			VideoID string `json:"videoId"`
+		} `json:"id"`
+	} `json:"items"`
+}
+
+// Client calls the YouTube Data API.
+type Client struct {
+	apiKey     string
+	httpClient *http.Client
+	cache      cache.Cache
+}
+
+// NewClient returns a Client. c may be nil to disable caching.
+func NewClient(apiKey string, c cache.Cache) *Client {
+	return &Client{apiKey: apiKey, httpClient: http.DefaultClient, cache: c}
+}
+
+// SearchTrailer returns the videoId of the top result for "<title> <year>
+// official trailer", or "" if none was found. Results are keyed in cache as
+// com.youtube.trailer.<title>.<year>.
+func (c *Client) SearchTrailer(title, year string) (string, error) {
+	key := fmt.Sprintf("com.youtube.trailer.%s.%s", title, year)
+
+	if c.cache != nil {
+		if cached, ok := c.cache.Get(key); ok {
+			return cached, nil
+		}
+	}
+
+	query := fmt.Sprintf("%s %s official trailer", title, year)
+	params := url.Values{
+		"part":       {"snippet"},
+		"q":          {query},
+		"type":       {"video"},
+		"key":        {c.apiKey},
+		"maxResults": {"1"},
+	}
+
+	resp, err := c.httpClient.Get(searchURL + "?" + params.Encode())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("youtube: search returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var parsed searchResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", err
+	}
+
+	videoID := ""
+	if len(parsed.Items) > 0 {
+		videoID = parsed.Items[0].ID.VideoID
+	}
+
+	if c.cache != nil {
+		c.cache.Set(key, videoID, trailerTTL)
+	}
+
+	return videoID, nil
+}
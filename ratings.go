@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/antmusumba/movielab/pkg/omdb"
+	"github.com/antmusumba/movielab/pkg/tmdb"
+)
+
+// Rating is a single source's score for a movie, normalized to a 0-100 scale
+// so callers can compare or average across sources.
+type Rating struct {
+	Source          string  `json:"source"`
+	Value           string  `json:"value"`
+	NormalizedScore float64 `json:"normalized_score"`
+}
+
+// ratingSourcePriority orders sources from most to least preferred, so the
+// UI can deterministically pick a single score when it only wants one.
+var ratingSourcePriority = []string{
+	"Rotten Tomatoes",
+	"Metacritic",
+	"Internet Movie Database",
+	"TMDB",
+}
+
+// PreferredRating returns the highest-priority rating present in ratings,
+// falling back to the first entry if none of the known sources match.
+func PreferredRating(ratings []Rating) *Rating {
+	for _, source := range ratingSourcePriority {
+		for i := range ratings {
+			if ratings[i].Source == source {
+				return &ratings[i]
+			}
+		}
+	}
+	if len(ratings) > 0 {
+		return &ratings[0]
+	}
+	return nil
+}
+
+// buildRatings merges TMDB's vote_average with OMDB's imdbRating, Metascore
+// and Ratings array into a single normalized 0-100 slice, deduplicated by
+// source. OMDB's Ratings array already carries "Internet Movie Database" and
+// "Metacritic" entries equivalent to the top-level imdbRating/Metascore
+// fields, so the array takes priority and the top-level fields only fill in
+// when their source is missing from it. Either argument may be nil if that
+// source hasn't been fetched yet.
+func buildRatings(details *tmdb.MovieDetails, omdbData *omdb.MovieResponse) []Rating {
+	var ratings []Rating
+	seen := map[string]bool{}
+
+	if details != nil && details.VoteAverage > 0 {
+		ratings = append(ratings, Rating{
+			Source:          "TMDB",
+			Value:           strconv.FormatFloat(details.VoteAverage, 'f', 1, 64),
+			NormalizedScore: details.VoteAverage * 10,
+		})
+		seen["TMDB"] = true
+	}
+
+	if omdbData != nil {
+		for _, r := range omdbData.Ratings {
+			if seen[r.Source] {
+				continue
+			}
+			score, ok := parseOMDBRatingValue(r.Value)
+			if !ok {
+				continue
+			}
+			ratings = append(ratings, Rating{
+				Source:          r.Source,
+				Value:           r.Value,
+				NormalizedScore: score,
+			})
+			seen[r.Source] = true
+		}
+
+		if !seen["Internet Movie Database"] {
+			if score, ok := parseOutOfTen(omdbData.IMDBRating); ok {
+				ratings = append(ratings, Rating{
+					Source:          "Internet Movie Database",
+					Value:           omdbData.IMDBRating,
+					NormalizedScore: score,
+				})
+				seen["Internet Movie Database"] = true
+			}
+		}
+		if !seen["Metacritic"] {
+			if score, ok := parsePercent100(omdbData.Metascore); ok {
+				ratings = append(ratings, Rating{
+					Source:          "Metacritic",
+					Value:           omdbData.Metascore,
+					NormalizedScore: score,
+				})
+				seen["Metacritic"] = true
+			}
+		}
+	}
+
+	return ratings
+}
+
+// parseOMDBRatingValue normalizes one entry of OMDB's Ratings array to a
+// 0-100 score. Values look like "93%" (Rotten Tomatoes), "76/100"
+// (Metacritic), or "7.8/10" (Internet Movie Database).
+func parseOMDBRatingValue(value string) (float64, bool) {
+	if strings.HasSuffix(value, "%") {
+		return parsePercent100(strings.TrimSuffix(value, "%"))
+	}
+	if strings.HasSuffix(value, "/100") {
+		return parsePercent100(strings.TrimSuffix(value, "/100"))
+	}
+	if strings.HasSuffix(value, "/10") {
+		return parseOutOfTen(strings.TrimSuffix(value, "/10"))
+	}
+	return parsePercent100(value)
+}
+
+// parsePercent100 parses a value already on a 0-100 scale.
+func parsePercent100(value string) (float64, bool) {
+	if value == "" || value == "N/A" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// parseOutOfTen parses a value on a 0-10 scale and scales it to 0-100.
+func parseOutOfTen(value string) (float64, bool) {
+	if value == "" || value == "N/A" {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n * 10, true
+}
+
+// cachedRatingsFor returns the ratings already aggregated for movieID, if the
+// movie has already been through the enrichment pipeline, so list endpoints
+// can attach them without triggering a fresh fetch.
+func cachedRatingsFor(movieID int) []Rating {
+	cached, ok := getMovieCache(strconv.Itoa(movieID))
+	if !ok {
+		return nil
+	}
+	return cached.Ratings
+}
+
+// ratingsHandler returns just the aggregated ratings block for a movie, for
+// lightweight polling without the rest of the movie detail payload.
+func ratingsHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	movieID := vars["id"]
+
+	cached, ok := getMovieCache(movieID)
+	if !ok {
+		http.Error(w, "movie not found in cache yet; GET /api/movie/{id} first", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ratings":   cached.Ratings,
+		"preferred": PreferredRating(cached.Ratings),
+	})
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/antmusumba/movielab/pkg/omdb"
+	"github.com/antmusumba/movielab/pkg/tmdb"
+)
+
+func TestParseOMDBRatingValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   float64
+		wantOK bool
+	}{
+		{"rotten tomatoes percent", "93%", 93, true},
+		{"metacritic out of 100", "76/100", 76, true},
+		{"imdb out of 10", "7.8/10", 78, true},
+		{"not available", "N/A", 0, false},
+		{"empty", "", 0, false},
+		{"unparseable", "tbd", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseOMDBRatingValue(tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("parseOMDBRatingValue(%q) ok = %v, want %v", tt.value, ok, tt.wantOK)
+			}
+			if ok && math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("parseOMDBRatingValue(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRatings(t *testing.T) {
+	t.Run("nil sources yield no ratings", func(t *testing.T) {
+		got := buildRatings(nil, nil)
+		if len(got) != 0 {
+			t.Errorf("buildRatings(nil, nil) = %v, want empty", got)
+		}
+	})
+
+	t.Run("TMDB only", func(t *testing.T) {
+		details := &tmdb.MovieDetails{VoteAverage: 7.5}
+		got := buildRatings(details, nil)
+		if len(got) != 1 || got[0].Source != "TMDB" || got[0].NormalizedScore != 75 {
+			t.Errorf("buildRatings(TMDB only) = %+v, want single TMDB rating at 75", got)
+		}
+	})
+
+	t.Run("OMDB Ratings array takes priority over duplicate top-level fields", func(t *testing.T) {
+		omdbData := &omdb.MovieResponse{
+			IMDBRating: "7.8",
+			Metascore:  "76",
+			Ratings: []omdb.Rating{
+				{Source: "Internet Movie Database", Value: "7.8/10"},
+				{Source: "Rotten Tomatoes", Value: "93%"},
+				{Source: "Metacritic", Value: "76/100"},
+			},
+		}
+		got := buildRatings(nil, omdbData)
+
+		bySource := map[string]Rating{}
+		for _, r := range got {
+			if _, dup := bySource[r.Source]; dup {
+				t.Fatalf("buildRatings emitted duplicate source %q: %v", r.Source, got)
+			}
+			bySource[r.Source] = r
+		}
+
+		if len(got) != 3 {
+			t.Fatalf("buildRatings(array only) = %v, want 3 deduplicated ratings", got)
+		}
+		if bySource["Metacritic"].NormalizedScore != 76 {
+			t.Errorf("Metacritic score = %v, want 76", bySource["Metacritic"].NormalizedScore)
+		}
+	})
+
+	t.Run("top-level fields fill in when array omits that source", func(t *testing.T) {
+		omdbData := &omdb.MovieResponse{
+			IMDBRating: "7.8",
+			Metascore:  "76",
+			Ratings: []omdb.Rating{
+				{Source: "Rotten Tomatoes", Value: "93%"},
+			},
+		}
+		got := buildRatings(nil, omdbData)
+
+		bySource := map[string]Rating{}
+		for _, r := range got {
+			bySource[r.Source] = r
+		}
+
+		if len(got) != 3 {
+			t.Fatalf("buildRatings(array + fallback) = %v, want 3 ratings", got)
+		}
+		if _, ok := bySource["Internet Movie Database"]; !ok {
+			t.Errorf("expected fallback Internet Movie Database rating, got %v", got)
+		}
+		if r, ok := bySource["Metacritic"]; !ok || r.NormalizedScore != 76 {
+			t.Errorf("expected fallback Metacritic rating at 76, got %v", got)
+		}
+	})
+}
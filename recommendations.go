@@ -0,0 +1,248 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// recommendationLimit caps how many scored candidates are returned.
+const recommendationLimit = 20
+
+// discoverMinVoteCount filters out obscure discover/movie candidates.
+const discoverMinVoteCount = 100
+
+// genreVectorPreferenceKey is the user_preferences row the genre-frequency
+// vector is cached under, invalidated whenever the watchlist changes.
+const genreVectorPreferenceKey = "genre_vector"
+
+// watchlistEntry is the subset of a watchlist row the scoring engine needs.
+type watchlistEntry struct {
+	MovieID   int
+	Watched   bool
+	MediaType string
+}
+
+// scoredMovieRecommendations builds a genre-frequency vector from the
+// watchlist (weighting watched items 2x), discovers candidates in the top
+// genres, and ranks them by:
+//
+//	score = 0.5*normalized_vote_average + 0.3*genre_cosine_similarity + 0.2*recency_bonus
+func scoredMovieRecommendations(entries []watchlistEntry) ([]Movie, error) {
+	genreVector, err := genreVectorForWatchlist(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	topGenres := topGenreIDs(genreVector, 3)
+	if len(topGenres) == 0 {
+		return nil, nil
+	}
+
+	candidates, err := tmdbClient.Discover(topGenres, discoverMinVoteCount)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[int]bool, len(entries))
+	for _, e := range entries {
+		excluded[e.MovieID] = true
+	}
+
+	type scoredMovie struct {
+		movie Movie
+		score float64
+	}
+
+	currentYear := time.Now().Year()
+	var scored []scoredMovie
+	for _, c := range candidates.Results {
+		if excluded[c.ID] {
+			continue
+		}
+
+		score := 0.5*(c.VoteAverage/10) +
+			0.3*cosineSimilarity(c.GenreIDs, genreVector) +
+			0.2*recencyBonus(c.ReleaseDate, currentYear)
+
+		scored = append(scored, scoredMovie{
+			movie: Movie{
+				ID:          c.ID,
+				Title:       c.Title,
+				Overview:    c.Overview,
+				PosterPath:  c.PosterPath,
+				ReleaseDate: c.ReleaseDate,
+				Rating:      c.VoteAverage,
+				Ratings:     cachedRatingsFor(c.ID),
+				Type:        "movie",
+			},
+			score: score,
+		})
+	}
+
+	sort.Slice(scored, func(i, j int) bool { return scored[i].score > scored[j].score })
+	if len(scored) > recommendationLimit {
+		scored = scored[:recommendationLimit]
+	}
+
+	movies := make([]Movie, len(scored))
+	for i, s := range scored {
+		movies[i] = s.movie
+	}
+	return movies, nil
+}
+
+// genreVectorForWatchlist returns the cached genre-frequency vector if one
+// is still valid, otherwise rebuilds it from full TMDB details for every
+// watchlist entry and caches the result. A vector is only cached when every
+// entry resolved, so a transient TMDB outage can't poison the cache with an
+// empty or partial vector that then starves recommendations until the next
+// watchlist mutation invalidates it.
+func genreVectorForWatchlist(entries []watchlistEntry) (map[int]float64, error) {
+	if cached, ok := cachedGenreVector(); ok {
+		return cached, nil
+	}
+
+	vector := map[int]float64{}
+	complete := true
+	for _, e := range entries {
+		details, err := tmdbClient.GetMovieDetails(strconv.Itoa(e.MovieID), "")
+		if err != nil {
+			complete = false
+			continue
+		}
+
+		weight := 1.0
+		if e.Watched {
+			weight = 2.0
+		}
+		for _, g := range details.Genres {
+			vector[g.ID] += weight
+		}
+	}
+
+	if complete && len(vector) > 0 {
+		setCachedGenreVector(vector)
+	}
+	return vector, nil
+}
+
+// topGenreIDs returns the n genre IDs with the highest weight in vector.
+func topGenreIDs(vector map[int]float64, n int) []int {
+	type genreWeight struct {
+		id     int
+		weight float64
+	}
+
+	weights := make([]genreWeight, 0, len(vector))
+	for id, w := range vector {
+		weights = append(weights, genreWeight{id, w})
+	}
+	sort.Slice(weights, func(i, j int) bool { return weights[i].weight > weights[j].weight })
+
+	if len(weights) > n {
+		weights = weights[:n]
+	}
+	ids := make([]int, len(weights))
+	for i, gw := range weights {
+		ids[i] = gw.id
+	}
+	return ids
+}
+
+// cosineSimilarity compares a candidate's binary genre vector against the
+// watchlist's weighted genre-frequency vector.
+func cosineSimilarity(candidateGenres []int, vector map[int]float64) float64 {
+	if len(candidateGenres) == 0 || len(vector) == 0 {
+		return 0
+	}
+
+	candidateSet := make(map[int]bool, len(candidateGenres))
+	for _, id := range candidateGenres {
+		candidateSet[id] = true
+	}
+
+	var dot, vectorNorm float64
+	for id, w := range vector {
+		vectorNorm += w * w
+		if candidateSet[id] {
+			dot += w
+		}
+	}
+	if vectorNorm == 0 {
+		return 0
+	}
+
+	candidateNorm := math.Sqrt(float64(len(candidateGenres)))
+	return dot / (candidateNorm * math.Sqrt(vectorNorm))
+}
+
+// recencyBonus decays exponentially with a 5-year half-life-ish falloff.
+func recencyBonus(releaseDate string, currentYear int) float64 {
+	if len(releaseDate) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(releaseDate[:4])
+	if err != nil {
+		return 0
+	}
+
+	years := float64(currentYear - year)
+	if years < 0 {
+		years = 0
+	}
+	return math.Exp(-years / 5)
+}
+
+// cachedGenreVector returns the genre-frequency vector cached in
+// user_preferences, if present and non-empty. An empty cached vector is
+// treated as a miss rather than a valid (but unusable) result.
+func cachedGenreVector() (map[int]float64, bool) {
+	var value string
+	err := db.QueryRow("SELECT preference_value FROM user_preferences WHERE preference_key = ?", genreVectorPreferenceKey).Scan(&value)
+	if err != nil {
+		return nil, false
+	}
+
+	var raw map[string]float64
+	if json.Unmarshal([]byte(value), &raw) != nil || len(raw) == 0 {
+		return nil, false
+	}
+
+	vector := make(map[int]float64, len(raw))
+	for k, w := range raw {
+		id, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		vector[id] = w
+	}
+	return vector, true
+}
+
+// setCachedGenreVector persists the genre-frequency vector to user_preferences.
+func setCachedGenreVector(vector map[int]float64) error {
+	raw := make(map[string]float64, len(vector))
+	for id, w := range vector {
+		raw[strconv.Itoa(id)] = w
+	}
+
+	body, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(
+		"INSERT INTO user_preferences (preference_key, preference_value) VALUES (?, ?) ON CONFLICT(preference_key) DO UPDATE SET preference_value = excluded.preference_value",
+		genreVectorPreferenceKey, string(body),
+	)
+	return err
+}
+
+// invalidateGenreVector drops the cached genre vector so the next
+// recommendations request rebuilds it from the current watchlist.
+func invalidateGenreVector() {
+	db.Exec("DELETE FROM user_preferences WHERE preference_key = ?", genreVectorPreferenceKey)
+}
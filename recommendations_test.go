@@ -0,0 +1,122 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []int
+		vector     map[int]float64
+		want       float64
+	}{
+		{
+			name:       "identical single genre",
+			candidates: []int{28},
+			vector:     map[int]float64{28: 2},
+			want:       1,
+		},
+		{
+			name:       "no overlap",
+			candidates: []int{35},
+			vector:     map[int]float64{28: 2},
+			want:       0,
+		},
+		{
+			name:       "empty candidate genres",
+			candidates: nil,
+			vector:     map[int]float64{28: 2},
+			want:       0,
+		},
+		{
+			name:       "empty vector",
+			candidates: []int{28},
+			vector:     map[int]float64{},
+			want:       0,
+		},
+		{
+			name:       "partial overlap",
+			candidates: []int{28, 35},
+			vector:     map[int]float64{28: 1, 12: 1},
+			want:       1 / (math.Sqrt(2) * math.Sqrt(2)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cosineSimilarity(tt.candidates, tt.vector)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("cosineSimilarity(%v, %v) = %v, want %v", tt.candidates, tt.vector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecencyBonus(t *testing.T) {
+	tests := []struct {
+		name        string
+		releaseDate string
+		currentYear int
+		want        float64
+	}{
+		{"released this year", "2026-01-01", 2026, 1},
+		{"released 5 years ago", "2021-06-15", 2026, math.Exp(-1)},
+		{"future release clamps to zero years", "2030-01-01", 2026, 1},
+		{"empty release date", "", 2026, 0},
+		{"unparseable release date", "abcd-01-01", 2026, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := recencyBonus(tt.releaseDate, tt.currentYear)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("recencyBonus(%q, %d) = %v, want %v", tt.releaseDate, tt.currentYear, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopGenreIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		vector map[int]float64
+		n      int
+		want   []int
+	}{
+		{
+			name:   "fewer entries than n returns all",
+			vector: map[int]float64{28: 3, 35: 1},
+			n:      3,
+			want:   []int{28, 35},
+		},
+		{
+			name:   "truncates to top n by weight",
+			vector: map[int]float64{28: 5, 35: 1, 12: 3, 18: 4},
+			n:      2,
+			want:   []int{28, 18},
+		},
+		{
+			name:   "empty vector",
+			vector: map[int]float64{},
+			n:      3,
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := topGenreIDs(tt.vector, tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("topGenreIDs(%v, %d) = %v, want %v", tt.vector, tt.n, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("topGenreIDs(%v, %d) = %v, want %v", tt.vector, tt.n, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
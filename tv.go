@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// tvShowHandler returns details for a single TV show.
+func tvShowHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	show, err := tmdbClient.GetTVShow(vars["id"], "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(show)
+}
+
+// tvSeasonHandler returns a TV show's episodes for a single season.
+func tvSeasonHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	seasonNumber, err := strconv.Atoi(vars["season"])
+	if err != nil {
+		http.Error(w, "invalid season number", http.StatusBadRequest)
+		return
+	}
+
+	season, err := tmdbClient.GetSeason(vars["id"], seasonNumber, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(season)
+}
+
+// tvEpisodeHandler returns a single episode, including its guest stars.
+func tvEpisodeHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	seasonNumber, err := strconv.Atoi(vars["season"])
+	if err != nil {
+		http.Error(w, "invalid season number", http.StatusBadRequest)
+		return
+	}
+	episodeNumber, err := strconv.Atoi(vars["episode"])
+	if err != nil {
+		http.Error(w, "invalid episode number", http.StatusBadRequest)
+		return
+	}
+
+	episode, err := tmdbClient.GetEpisode(vars["id"], seasonNumber, episodeNumber, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(episode)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// watchlistExportRow is the flat shape used by the CSV export/import format,
+// mirroring what Letterboxd/Trakt exports look like.
+type watchlistExportRow struct {
+	IMDbID  string `json:"imdb_id"`
+	TMDbID  string `json:"tmdb_id"`
+	Title   string `json:"title"`
+	Year    string `json:"year"`
+	Watched bool   `json:"watched"`
+	AddedAt string `json:"added_at"`
+}
+
+var watchlistCSVHeader = []string{"imdb_id", "tmdb_id", "title", "year", "watched", "added_at"}
+
+// traktHistoryEntry is one entry of a Trakt history.json export.
+type traktHistoryEntry struct {
+	WatchedAt string `json:"watched_at"`
+	Movie     struct {
+		Title string `json:"title"`
+		Year  int    `json:"year"`
+		IDs   struct {
+			TMDb int    `json:"tmdb"`
+			IMDb string `json:"imdb"`
+		} `json:"ids"`
+	} `json:"movie"`
+}
+
+// exportWatchlistHandler streams the watchlist as JSON (default) or CSV,
+// selected via ?format=json|csv.
+func exportWatchlistHandler(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+
+	rows, err := db.Query("SELECT movie_id, title, watched, added_at FROM watchlist ORDER BY added_at DESC")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	var entries []watchlistExportRow
+	for rows.Next() {
+		var movieID int
+		var entry watchlistExportRow
+		if err := rows.Scan(&movieID, &entry.Title, &entry.Watched, &entry.AddedAt); err != nil {
+			continue
+		}
+		entry.TMDbID = strconv.Itoa(movieID)
+
+		if cached, ok := getMovieCache(entry.TMDbID); ok {
+			entry.IMDbID = cached.IMDBID
+			entry.Year = releaseYear(cached.ReleaseDate)
+		}
+		entries = append(entries, entry)
+	}
+
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=watchlist.csv")
+		writer := csv.NewWriter(w)
+		writer.Write(watchlistCSVHeader)
+		for _, entry := range entries {
+			writer.Write([]string{
+				entry.IMDbID, entry.TMDbID, entry.Title, entry.Year,
+				strconv.FormatBool(entry.Watched), entry.AddedAt,
+			})
+		}
+		writer.Flush()
+
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+	}
+}
+
+// importWatchlistHandler accepts a multipart file upload (field "file") in
+// JSON, CSV or Trakt history.json format, selected via ?format=, and adds
+// any entries not already in the watchlist.
+func importWatchlistHandler(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "missing file field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+
+	var rows []watchlistExportRow
+	switch format {
+	case "csv":
+		rows, err = parseCSVImport(data)
+	case "trakt":
+		rows, err = parseTraktImport(data)
+	default:
+		rows, err = parseJSONImport(data)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	imported, skipped, failed := importWatchlistRows(rows)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{
+		"imported": imported,
+		"skipped":  skipped,
+		"failed":   failed,
+	})
+}
+
+// parseJSONImport parses a JSON array of watchlistExportRow.
+func parseJSONImport(data []byte) ([]watchlistExportRow, error) {
+	var rows []watchlistExportRow
+	err := json.Unmarshal(data, &rows)
+	return rows, err
+}
+
+// parseCSVImport parses the same column layout exportWatchlistHandler
+// produces: imdb_id,tmdb_id,title,year,watched,added_at.
+func parseCSVImport(data []byte) ([]watchlistExportRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	var rows []watchlistExportRow
+	for _, record := range records[1:] { // skip header
+		if len(record) < 6 {
+			continue
+		}
+		rows = append(rows, watchlistExportRow{
+			IMDbID:  record[0],
+			TMDbID:  record[1],
+			Title:   record[2],
+			Year:    record[3],
+			Watched: record[4] == "true",
+			AddedAt: record[5],
+		})
+	}
+	return rows, nil
+}
+
+// parseTraktImport parses a Trakt history.json export, where each entry
+// identifies the movie by TMDB and/or IMDb ID under movie.ids.
+func parseTraktImport(data []byte) ([]watchlistExportRow, error) {
+	var history []traktHistoryEntry
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+
+	rows := make([]watchlistExportRow, 0, len(history))
+	for _, entry := range history {
+		row := watchlistExportRow{
+			IMDbID:  entry.Movie.IDs.IMDb,
+			Title:   entry.Movie.Title,
+			Watched: true,
+			AddedAt: entry.WatchedAt,
+		}
+		if entry.Movie.IDs.TMDb > 0 {
+			row.TMDbID = strconv.Itoa(entry.Movie.IDs.TMDb)
+		}
+		if entry.Movie.Year > 0 {
+			row.Year = strconv.Itoa(entry.Movie.Year)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// importWatchlistRows resolves missing TMDB IDs via IMDb lookup, skips rows
+// that duplicate an existing watchlist entry, and inserts the rest.
+func importWatchlistRows(rows []watchlistExportRow) (imported, skipped, failed int) {
+	existing := map[string]bool{}
+	existingRows, err := db.Query("SELECT movie_id FROM watchlist")
+	if err == nil {
+		defer existingRows.Close()
+		for existingRows.Next() {
+			var movieID int
+			if existingRows.Scan(&movieID) == nil {
+				existing[strconv.Itoa(movieID)] = true
+			}
+		}
+	}
+
+	for _, row := range rows {
+		if row.TMDbID == "" && row.IMDbID != "" {
+			row.TMDbID = resolveTMDbID(row.IMDbID)
+		}
+		if row.TMDbID == "" {
+			failed++
+			continue
+		}
+		if existing[row.TMDbID] {
+			skipped++
+			continue
+		}
+
+		movieID, err := strconv.Atoi(row.TMDbID)
+		if err != nil {
+			failed++
+			continue
+		}
+
+		addedAt := row.AddedAt
+		if addedAt == "" {
+			addedAt = time.Now().Format("2006-01-02 15:04:05")
+		}
+
+		_, err = db.Exec(
+			"INSERT INTO watchlist (movie_id, title, watched, added_at, media_type) VALUES (?, ?, ?, ?, 'movie')",
+			movieID, row.Title, row.Watched, addedAt)
+		if err != nil {
+			failed++
+			continue
+		}
+
+		existing[row.TMDbID] = true
+		imported++
+	}
+
+	invalidateGenreVector()
+	return imported, skipped, failed
+}
+
+// resolveTMDbID looks up a TMDB movie ID by its IMDb ID via TMDB's /find
+// endpoint. Returns "" if no match was found.
+func resolveTMDbID(imdbID string) string {
+	found, err := tmdbClient.Find(imdbID, "imdb_id")
+	if err != nil || len(found.MovieResults) == 0 {
+		return ""
+	}
+	return strconv.Itoa(found.MovieResults[0].ID)
+}
@@ -0,0 +1,90 @@
+package main
+
+import "testing"
+
+func TestParseCSVImport(t *testing.T) {
+	t.Run("parses rows and skips the header", func(t *testing.T) {
+		data := "imdb_id,tmdb_id,title,year,watched,added_at\n" +
+			"tt0111161,278,The Shawshank Redemption,1994,true,2024-01-01 00:00:00\n" +
+			"tt0068646,238,The Godfather,1972,false,2024-01-02 00:00:00\n"
+
+		rows, err := parseCSVImport([]byte(data))
+		if err != nil {
+			t.Fatalf("parseCSVImport returned error: %v", err)
+		}
+		if len(rows) != 2 {
+			t.Fatalf("parseCSVImport returned %d rows, want 2", len(rows))
+		}
+
+		want := watchlistExportRow{
+			IMDbID: "tt0111161", TMDbID: "278", Title: "The Shawshank Redemption",
+			Year: "1994", Watched: true, AddedAt: "2024-01-01 00:00:00",
+		}
+		if rows[0] != want {
+			t.Errorf("rows[0] = %+v, want %+v", rows[0], want)
+		}
+		if rows[1].Watched {
+			t.Errorf("rows[1].Watched = true, want false")
+		}
+	})
+
+	t.Run("malformed row with wrong field count errors", func(t *testing.T) {
+		data := "imdb_id,tmdb_id,title,year,watched,added_at\n" + "tt0111161,278,Incomplete Row\n"
+		if _, err := parseCSVImport([]byte(data)); err == nil {
+			t.Error("parseCSVImport returned no error for a row with the wrong field count")
+		}
+	})
+
+	t.Run("header only", func(t *testing.T) {
+		data := "imdb_id,tmdb_id,title,year,watched,added_at\n"
+		rows, err := parseCSVImport([]byte(data))
+		if err != nil {
+			t.Fatalf("parseCSVImport returned error: %v", err)
+		}
+		if len(rows) != 0 {
+			t.Errorf("parseCSVImport returned %d rows, want 0", len(rows))
+		}
+	})
+}
+
+func TestParseTraktImport(t *testing.T) {
+	data := `[
+		{
+			"watched_at": "2024-01-01T00:00:00.000Z",
+			"movie": {
+				"title": "The Shawshank Redemption",
+				"year": 1994,
+				"ids": {"tmdb": 278, "imdb": "tt0111161"}
+			}
+		},
+		{
+			"watched_at": "2024-01-02T00:00:00.000Z",
+			"movie": {
+				"title": "Missing TMDB ID",
+				"year": 2001,
+				"ids": {"tmdb": 0, "imdb": "tt0111162"}
+			}
+		}
+	]`
+
+	rows, err := parseTraktImport([]byte(data))
+	if err != nil {
+		t.Fatalf("parseTraktImport returned error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("parseTraktImport returned %d rows, want 2", len(rows))
+	}
+
+	first := rows[0]
+	if first.TMDbID != "278" || first.IMDbID != "tt0111161" || first.Year != "1994" || !first.Watched {
+		t.Errorf("rows[0] = %+v, want tmdb 278, imdb tt0111161, year 1994, watched", first)
+	}
+
+	second := rows[1]
+	if second.TMDbID != "" {
+		t.Errorf("rows[1].TMDbID = %q, want empty since tmdb id was 0", second.TMDbID)
+	}
+	if second.IMDbID != "tt0111162" {
+		t.Errorf("rows[1].IMDbID = %q, want tt0111162", second.IMDbID)
+	}
+}